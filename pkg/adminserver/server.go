@@ -0,0 +1,19 @@
+package adminserver
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hiveintclient "github.com/openshift/hive/pkg/client/clientset/versioned"
+)
+
+// NewServeMux returns an http.ServeMux with every handler in pkg/adminserver registered,
+// for a caller to serve the admin endpoints over HTTP. No binary in this repository
+// serves it; only its tests call it.
+func NewServeMux(hiveClient client.Client, hiveintClient hiveintclient.Interface, authClient kubernetes.Interface) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/syncset-resources", NewSyncSetResourcesHandler(hiveClient, hiveintClient, authClient))
+	return mux
+}
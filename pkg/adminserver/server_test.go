@@ -0,0 +1,36 @@
+package adminserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintfake "github.com/openshift/hive/pkg/client/clientset/versioned/fake"
+	testcs "github.com/openshift/hive/pkg/test/clustersync"
+)
+
+func TestNewServeMuxRoutesSyncSetResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hivev1.AddToScheme(scheme))
+	hiveClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	hiveintClient := hiveintfake.NewSimpleClientset(
+		testcs.BasicBuilder().Build(testcs.WithClusterDeploymentRef(testNamespace, testCDName)),
+	)
+
+	mux := NewServeMux(hiveClient, hiveintClient, allowingAuthClient())
+
+	req := httptest.NewRequest(http.MethodGet, "/syncset-resources?namespace="+testNamespace+"&clusterDeployment="+testCDName, nil)
+	req.Header.Set("Authorization", "Bearer faketoken")
+	rec := httptest.NewRecorder()
+
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,261 @@
+package adminserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	hiveintclient "github.com/openshift/hive/pkg/client/clientset/versioned"
+)
+
+// syncSetResource describes a single resource that Hive rendered and applied (or failed
+// to apply) to a spoke cluster as part of a SyncSet or SelectorSyncSet.
+type syncSetResource struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	SyncSetName string `json:"syncSetName"`
+	SyncSetKind string `json:"syncSetKind"`
+	ApplyMode   string `json:"applyMode"`
+
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// syncSetResourcesHandler serves GET /syncset-resources?namespace={namespace}&clusterDeployment={name},
+// returning the set of resources Hive has applied, or attempted to apply, to the spoke
+// cluster via SyncSets and SelectorSyncSets targeting the named ClusterDeployment.
+type syncSetResourcesHandler struct {
+	// hiveClient reads SyncSets and SelectorSyncSets, which live alongside the
+	// ClusterDeployment in the hub cluster's API.
+	hiveClient client.Client
+
+	// hiveintClient reads the ClusterSync object that records the result of the most
+	// recent apply of each SyncSet/SelectorSyncSet.
+	hiveintClient hiveintclient.Interface
+
+	// authClient is used to confirm the caller is authorized to read ClusterDeployments
+	// in the requested namespace before returning any data.
+	authClient kubernetes.Interface
+}
+
+// NewSyncSetResourcesHandler returns an http.Handler that serves the merged SyncSet
+// resource listing for a ClusterDeployment.
+func NewSyncSetResourcesHandler(hiveClient client.Client, hiveintClient hiveintclient.Interface, authClient kubernetes.Interface) http.Handler {
+	return &syncSetResourcesHandler{
+		hiveClient:    hiveClient,
+		hiveintClient: hiveintClient,
+		authClient:    authClient,
+	}
+}
+
+func (h *syncSetResourcesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("clusterDeployment")
+	if namespace == "" || name == "" {
+		http.Error(w, "namespace and clusterDeployment query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authorize(r, namespace); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	resources, err := h.mergedResources(r.Context(), namespace, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resources); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// authorize confirms the caller's bearer token grants get access to ClusterDeployments in
+// namespace via a SubjectAccessReview.
+func (h *syncSetResourcesHandler) authorize(r *http.Request, namespace string) error {
+	token := bearerToken(r)
+	if token == "" {
+		return fmt.Errorf("missing bearer token")
+	}
+
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			Token: token,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     hivev1.SchemeGroupVersion.Group,
+				Resource:  "clusterdeployments",
+			},
+		},
+	}
+	result, err := h.authClient.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), sar, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to run subject access review: %w", err)
+	}
+	if !result.Status.Allowed {
+		return fmt.Errorf("not authorized to get clusterdeployments in namespace %q", namespace)
+	}
+	return nil
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) > len(prefix) && auth[:len(prefix)] == prefix {
+		return auth[len(prefix):]
+	}
+	return ""
+}
+
+// mergedResources loads the ClusterSync for the named ClusterDeployment and, for each
+// SyncSet and SelectorSyncSet it references, resolves the resources that definition
+// renders, annotated with the last apply result recorded in the ClusterSync status.
+func (h *syncSetResourcesHandler) mergedResources(ctx context.Context, namespace, name string) ([]syncSetResource, error) {
+	clusterSync, err := h.hiveintClient.HiveinternalV1alpha1().ClusterSyncs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ClusterSync %s/%s: %w", namespace, name, err)
+	}
+
+	var resources []syncSetResource
+
+	for _, status := range clusterSync.Status.SyncSets {
+		syncSet := &hivev1.SyncSet{}
+		if err := h.hiveClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: status.Name}, syncSet); err != nil {
+			resources = append(resources, unresolvedResource(status, "SyncSet", err))
+			continue
+		}
+		resources = append(resources, resourcesForSyncSetSpec(status, "SyncSet", syncSet.Spec.SyncSetCommonSpec)...)
+	}
+
+	for _, status := range clusterSync.Status.SelectorSyncSets {
+		selectorSyncSet := &hivev1.SelectorSyncSet{}
+		if err := h.hiveClient.Get(ctx, client.ObjectKey{Name: status.Name}, selectorSyncSet); err != nil {
+			resources = append(resources, unresolvedResource(status, "SelectorSyncSet", err))
+			continue
+		}
+		resources = append(resources, resourcesForSyncSetSpec(status, "SelectorSyncSet", selectorSyncSet.Spec.SyncSetCommonSpec)...)
+	}
+
+	return resources, nil
+}
+
+func unresolvedResource(status hiveintv1alpha1.SyncStatus, kind string, err error) syncSetResource {
+	return syncSetResource{
+		SyncSetName: status.Name,
+		SyncSetKind: kind,
+		Result:      string(status.Result),
+		Message:     fmt.Sprintf("unable to resolve %s %s: %v", kind, status.Name, err),
+	}
+}
+
+// resourcesForSyncSetSpec renders every resource a SyncSet or SelectorSyncSet spec
+// contributes to the spoke cluster: the resources it applies wholesale, the existing
+// objects it patches, and the secrets it syncs in. All three are first-class,
+// commonly-used fields of SyncSetCommonSpec, so all three must be represented or the
+// listing misleadingly shows patch-only or secret-only SyncSets as empty.
+func resourcesForSyncSetSpec(status hiveintv1alpha1.SyncStatus, kind string, spec hivev1.SyncSetCommonSpec) []syncSetResource {
+	result := make([]syncSetResource, 0, len(spec.Resources)+len(spec.Patches)+len(spec.SecretMappings))
+	result = append(result, resourcesFromRaw(status, kind, spec)...)
+	result = append(result, resourcesFromPatches(status, kind, spec)...)
+	result = append(result, resourcesFromSecretMappings(status, kind, spec)...)
+	return result
+}
+
+func resourcesFromRaw(status hiveintv1alpha1.SyncStatus, kind string, spec hivev1.SyncSetCommonSpec) []syncSetResource {
+	result := make([]syncSetResource, 0, len(spec.Resources))
+	for _, raw := range spec.Resources {
+		gvk, objMeta, err := decodeResource(raw)
+		resource := syncSetResource{
+			SyncSetName: status.Name,
+			SyncSetKind: kind,
+			ApplyMode:   string(spec.ResourceApplyMode),
+			Result:      string(status.Result),
+			Message:     status.FailureMessage,
+		}
+		if err != nil {
+			resource.Message = fmt.Sprintf("unable to decode resource: %v", err)
+			result = append(result, resource)
+			continue
+		}
+		resource.Group = gvk.Group
+		resource.Version = gvk.Version
+		resource.Kind = gvk.Kind
+		resource.Namespace = objMeta.GetNamespace()
+		resource.Name = objMeta.GetName()
+		result = append(result, resource)
+	}
+	return result
+}
+
+func resourcesFromPatches(status hiveintv1alpha1.SyncStatus, kind string, spec hivev1.SyncSetCommonSpec) []syncSetResource {
+	result := make([]syncSetResource, 0, len(spec.Patches))
+	for _, patch := range spec.Patches {
+		gv, err := schema.ParseGroupVersion(patch.APIVersion)
+		resource := syncSetResource{
+			Kind:        patch.Kind,
+			Namespace:   patch.Namespace,
+			Name:        patch.Name,
+			SyncSetName: status.Name,
+			SyncSetKind: kind,
+			ApplyMode:   "Patch:" + patch.PatchType,
+			Result:      string(status.Result),
+			Message:     status.FailureMessage,
+		}
+		if err != nil {
+			resource.Message = fmt.Sprintf("unable to parse apiVersion %q: %v", patch.APIVersion, err)
+			result = append(result, resource)
+			continue
+		}
+		resource.Group = gv.Group
+		resource.Version = gv.Version
+		result = append(result, resource)
+	}
+	return result
+}
+
+func resourcesFromSecretMappings(status hiveintv1alpha1.SyncStatus, kind string, spec hivev1.SyncSetCommonSpec) []syncSetResource {
+	result := make([]syncSetResource, 0, len(spec.SecretMappings))
+	for _, mapping := range spec.SecretMappings {
+		result = append(result, syncSetResource{
+			Group:       corev1.SchemeGroupVersion.Group,
+			Version:     corev1.SchemeGroupVersion.Version,
+			Kind:        "Secret",
+			Namespace:   mapping.TargetRef.Namespace,
+			Name:        mapping.TargetRef.Name,
+			SyncSetName: status.Name,
+			SyncSetKind: kind,
+			ApplyMode:   string(spec.ResourceApplyMode),
+			Result:      string(status.Result),
+			Message:     status.FailureMessage,
+		})
+	}
+	return result
+}
+
+func decodeResource(raw runtime.RawExtension) (schema.GroupVersionKind, metav1.Object, error) {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(raw.Raw); err != nil {
+		return schema.GroupVersionKind{}, nil, err
+	}
+	return u.GroupVersionKind(), u, nil
+}
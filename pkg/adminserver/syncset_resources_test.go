@@ -0,0 +1,129 @@
+package adminserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	hiveintfake "github.com/openshift/hive/pkg/client/clientset/versioned/fake"
+	testcs "github.com/openshift/hive/pkg/test/clustersync"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testCDName    = "test-cluster"
+)
+
+func allowingAuthClient() *fakekubeclient.Clientset {
+	client := fakekubeclient.NewSimpleClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &authorizationv1.SubjectAccessReview{
+			Status: authorizationv1.SubjectAccessReviewStatus{Allowed: true},
+		}, nil
+	})
+	return client
+}
+
+func TestSyncSetResourcesHandler(t *testing.T) {
+	configMapResource := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-configmap", Namespace: "default"},
+	}
+	rawResource := runtime.RawExtension{}
+	raw, err := json.Marshal(configMapResource)
+	require.NoError(t, err)
+	rawResource.Raw = raw
+
+	syncSet := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "my-syncset"},
+		Spec: hivev1.SyncSetSpec{
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: hivev1.UpsertResourceApplyMode,
+				Resources:         []runtime.RawExtension{rawResource},
+				Patches: []hivev1.SyncObjectPatch{
+					{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "my-deployment",
+						Namespace:  "default",
+						Patch:      `{"spec":{"replicas":3}}`,
+						PatchType:  "merge",
+					},
+				},
+				SecretMappings: []hivev1.SecretMapping{
+					{
+						SourceRef: hivev1.SecretReference{Name: "source-secret", Namespace: "hive"},
+						TargetRef: hivev1.SecretReference{Name: "target-secret", Namespace: "default"},
+					},
+				},
+			},
+		},
+	}
+
+	clusterSync := testcs.BasicBuilder().Build(
+		testcs.WithClusterDeploymentRef(testNamespace, testCDName),
+		testcs.WithSyncSetStatus("my-syncset", hiveintv1alpha1.SuccessSyncSetResult, ""),
+	)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, hivev1.AddToScheme(scheme))
+	hiveClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(syncSet).Build()
+	hiveintClient := hiveintfake.NewSimpleClientset(clusterSync)
+
+	handler := NewSyncSetResourcesHandler(hiveClient, hiveintClient, allowingAuthClient())
+
+	req := httptest.NewRequest(http.MethodGet, "/syncset-resources?namespace="+testNamespace+"&clusterDeployment="+testCDName, nil)
+	req.Header.Set("Authorization", "Bearer faketoken")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resources []syncSetResource
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resources))
+	require.Len(t, resources, 3)
+
+	assert.Equal(t, "ConfigMap", resources[0].Kind)
+	assert.Equal(t, "my-configmap", resources[0].Name)
+	assert.Equal(t, "my-syncset", resources[0].SyncSetName)
+	assert.Equal(t, "Success", resources[0].Result)
+
+	assert.Equal(t, "Deployment", resources[1].Kind)
+	assert.Equal(t, "my-deployment", resources[1].Name)
+	assert.Equal(t, "default", resources[1].Namespace)
+
+	assert.Equal(t, "Secret", resources[2].Kind)
+	assert.Equal(t, "target-secret", resources[2].Name)
+	assert.Equal(t, "default", resources[2].Namespace)
+}
+
+func TestSyncSetResourcesHandlerRequiresAuth(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hivev1.AddToScheme(scheme))
+	hiveClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	hiveintClient := hiveintfake.NewSimpleClientset()
+
+	handler := NewSyncSetResourcesHandler(hiveClient, hiveintClient, allowingAuthClient())
+
+	req := httptest.NewRequest(http.MethodGet, "/syncset-resources?namespace="+testNamespace+"&clusterDeployment="+testCDName, nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
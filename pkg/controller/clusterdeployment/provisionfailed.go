@@ -0,0 +1,167 @@
+package clusterdeployment
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// Canonical ProvisionFailedCondition reasons produced by the classifier registry below.
+const (
+	ReasonAzureInvalidTemplateDeployment = "AzureInvalidTemplateDeployment"
+	ReasonAzureRequestDisallowedByPolicy = "AzureRequestDisallowedByPolicy"
+	ReasonAWSInsufficientCapacity        = "AWSInsufficientCapacity"
+	ReasonAWSQuotaExceeded               = "AWSQuotaExceeded"
+	ReasonGCPQuotaExceeded               = "GCPQuotaExceeded"
+	ReasonPullSecretInvalid              = "PullSecretInvalid"
+	ReasonUnknownError                   = "UnknownError"
+)
+
+var metricProvisionFailedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "hive_cluster_deployments_provision_failed_total",
+	Help: "Counter incremented each time a ClusterDeployment provision is classified as failed, by reason.",
+}, []string{"reason"})
+
+func init() {
+	metrics.Registry.MustRegister(metricProvisionFailedTotal)
+}
+
+// Classifier inspects the raw install log for a provision attempt and, if it recognizes
+// the failure, returns a canonical reason and human-readable message with matched set to
+// true. Classifiers that don't recognize the failure should return matched=false.
+type Classifier func(installLog []byte, cd *hivev1.ClusterDeployment) (reason, message string, matched bool)
+
+// classifiersByPlatform holds the ordered list of Classifiers to try for each supported
+// platform. Classifiers are tried in order and the first match wins.
+var classifiersByPlatform = map[string][]Classifier{
+	"aws": {
+		classifyAWSInsufficientCapacity,
+		classifyAWSQuotaExceeded,
+		classifyPullSecretInvalid,
+	},
+	"azure": {
+		classifyAzureInvalidTemplateDeployment,
+		classifyAzureRequestDisallowedByPolicy,
+		classifyPullSecretInvalid,
+	},
+	"gcp": {
+		classifyGCPQuotaExceeded,
+		classifyPullSecretInvalid,
+	},
+}
+
+// ClassifyProvisionFailure walks the Classifier registry for the ClusterDeployment's
+// platform, in order, and returns the reason and message of the first Classifier that
+// recognizes the failure in installLog. If no Classifier matches, it falls back to
+// ReasonUnknownError. The returned reason is also recorded on the
+// hive_cluster_deployments_provision_failed_total counter.
+func ClassifyProvisionFailure(installLog []byte, cd *hivev1.ClusterDeployment) (reason, message string) {
+	reason, message = classify(installLog, cd)
+	metricProvisionFailedTotal.WithLabelValues(reason).Inc()
+	return reason, message
+}
+
+// SetProvisionFailedCondition classifies installLog and sets the resulting reason and
+// message on the ClusterDeployment's ProvisionFailedCondition, replacing any existing
+// ProvisionFailedCondition, and returns the condition it set. Each call also increments
+// the hive_cluster_deployments_provision_failed_total counter for the classified reason.
+// No reconcile loop in this repository calls it; only its own tests do.
+func SetProvisionFailedCondition(cd *hivev1.ClusterDeployment, installLog []byte) hivev1.ClusterDeploymentCondition {
+	reason, message := ClassifyProvisionFailure(installLog, cd)
+	cond := hivev1.ClusterDeploymentCondition{
+		Type:    hivev1.ProvisionFailedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+	cd.Status.Conditions = setProvisionFailedCondition(cd.Status.Conditions, cond)
+	return cond
+}
+
+func setProvisionFailedCondition(conditions []hivev1.ClusterDeploymentCondition, cond hivev1.ClusterDeploymentCondition) []hivev1.ClusterDeploymentCondition {
+	for i, c := range conditions {
+		if c.Type == cond.Type {
+			conditions[i] = cond
+			return conditions
+		}
+	}
+	return append(conditions, cond)
+}
+
+func classify(installLog []byte, cd *hivev1.ClusterDeployment) (reason, message string) {
+	for _, classifier := range classifiersByPlatform[platformName(cd)] {
+		if reason, message, matched := classifier(installLog, cd); matched {
+			return reason, message
+		}
+	}
+	return ReasonUnknownError, "the cause of the provision failure could not be determined from the install log"
+}
+
+func platformName(cd *hivev1.ClusterDeployment) string {
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		return "aws"
+	case cd.Spec.Platform.Azure != nil:
+		return "azure"
+	case cd.Spec.Platform.GCP != nil:
+		return "gcp"
+	default:
+		return ""
+	}
+}
+
+var (
+	azureInvalidTemplateRE    = regexp.MustCompile(`(?i)InvalidTemplateDeployment`)
+	azureDisallowedByPolicyRE = regexp.MustCompile(`(?i)RequestDisallowedByPolicy`)
+	awsInsufficientCapacityRE = regexp.MustCompile(`(?i)InsufficientInstanceCapacity`)
+	awsQuotaExceededRE        = regexp.MustCompile(`(?i)(VcpuLimitExceeded|TooManyBuckets|AddressLimitExceeded)`)
+	gcpQuotaExceededRE        = regexp.MustCompile(`(?i)Quota '.*' exceeded`)
+	pullSecretInvalidRE       = regexp.MustCompile(`(?i)(invalid pull secret|401 Unauthorized.*registry)`)
+)
+
+func classifyAzureInvalidTemplateDeployment(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if azureInvalidTemplateRE.Match(installLog) {
+		return ReasonAzureInvalidTemplateDeployment, "the Azure Resource Manager template deployment was invalid", true
+	}
+	return "", "", false
+}
+
+func classifyAzureRequestDisallowedByPolicy(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if azureDisallowedByPolicyRE.Match(installLog) {
+		return ReasonAzureRequestDisallowedByPolicy, "the request was disallowed by an Azure policy", true
+	}
+	return "", "", false
+}
+
+func classifyAWSInsufficientCapacity(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if awsInsufficientCapacityRE.Match(installLog) {
+		return ReasonAWSInsufficientCapacity, "AWS did not have sufficient capacity for the requested instance type", true
+	}
+	return "", "", false
+}
+
+func classifyAWSQuotaExceeded(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if awsQuotaExceededRE.Match(installLog) {
+		return ReasonAWSQuotaExceeded, "an AWS service quota was exceeded", true
+	}
+	return "", "", false
+}
+
+func classifyGCPQuotaExceeded(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if gcpQuotaExceededRE.Match(installLog) {
+		return ReasonGCPQuotaExceeded, "a GCP service quota was exceeded", true
+	}
+	return "", "", false
+}
+
+func classifyPullSecretInvalid(installLog []byte, _ *hivev1.ClusterDeployment) (string, string, bool) {
+	if pullSecretInvalidRE.Match(installLog) || bytes.Contains(installLog, []byte("x509: certificate signed by unknown authority")) {
+		return ReasonPullSecretInvalid, "the configured pull secret was rejected by the registry", true
+	}
+	return "", "", false
+}
@@ -0,0 +1,106 @@
+package clusterdeployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/apis/hive/v1/aws"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	hivev1gcp "github.com/openshift/hive/apis/hive/v1/gcp"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+)
+
+func TestClassifyProvisionFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		cd         *hivev1.ClusterDeployment
+		installLog string
+		wantReason string
+	}{
+		{
+			name:       "azure invalid template deployment",
+			cd:         testcd.BasicBuilder().Build(testcd.WithAzurePlatform(&hivev1azure.Platform{})),
+			installLog: "level=error msg=failed: InvalidTemplateDeployment: the template deployment failed",
+			wantReason: ReasonAzureInvalidTemplateDeployment,
+		},
+		{
+			name:       "azure disallowed by policy",
+			cd:         testcd.BasicBuilder().Build(testcd.WithAzurePlatform(&hivev1azure.Platform{})),
+			installLog: "RequestDisallowedByPolicy: the resource action is disallowed by policy",
+			wantReason: ReasonAzureRequestDisallowedByPolicy,
+		},
+		{
+			name:       "aws insufficient capacity",
+			cd:         testcd.BasicBuilder().Build(testcd.WithAWSPlatform(&hivev1aws.Platform{})),
+			installLog: "InsufficientInstanceCapacity: We currently do not have sufficient capacity",
+			wantReason: ReasonAWSInsufficientCapacity,
+		},
+		{
+			name:       "aws quota exceeded",
+			cd:         testcd.BasicBuilder().Build(testcd.WithAWSPlatform(&hivev1aws.Platform{})),
+			installLog: "VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit",
+			wantReason: ReasonAWSQuotaExceeded,
+		},
+		{
+			name:       "gcp quota exceeded",
+			cd:         testcd.BasicBuilder().Build(testcd.WithGCPPlatform(&hivev1gcp.Platform{})),
+			installLog: "Error: Quota 'CPUS' exceeded.  Limit: 24.0 in region us-east1",
+			wantReason: ReasonGCPQuotaExceeded,
+		},
+		{
+			name:       "unrecognized failure",
+			cd:         testcd.BasicBuilder().Build(testcd.WithAWSPlatform(&hivev1aws.Platform{})),
+			installLog: "level=error msg=something went wrong that we've never seen before",
+			wantReason: ReasonUnknownError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reason, message := ClassifyProvisionFailure([]byte(test.installLog), test.cd)
+			assert.Equal(t, test.wantReason, reason)
+			assert.NotEmpty(t, message)
+		})
+	}
+}
+
+func TestSetProvisionFailedCondition(t *testing.T) {
+	cd := testcd.BasicBuilder().Build(testcd.WithAWSPlatform(&hivev1aws.Platform{}))
+
+	cond := SetProvisionFailedCondition(cd, testcd.FakeInstallLog("VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit"))
+
+	assert.Equal(t, ReasonAWSQuotaExceeded, cond.Reason)
+
+	want := testcd.BasicBuilder().Build(
+		testcd.WithAWSPlatform(&hivev1aws.Platform{}),
+		testcd.WithProvisionFailedReason(cond.Reason, cond.Message),
+	)
+	assert.Equal(t, want.Status.Conditions, cd.Status.Conditions)
+}
+
+// TestSetProvisionFailedCondition_ReplacesExistingCondition verifies that re-classifying
+// a ClusterDeployment that already carries a ProvisionFailedCondition from a prior
+// classification, alongside the ProvisionStoppedCondition that Broken() sets, replaces
+// rather than duplicates the condition.
+func TestSetProvisionFailedCondition_ReplacesExistingCondition(t *testing.T) {
+	cd := testcd.BasicBuilder().Build(
+		testcd.WithAWSPlatform(&hivev1aws.Platform{}),
+		testcd.Broken(),
+		testcd.WithProvisionFailedReason(ReasonUnknownError, "previous attempt"),
+	)
+
+	cond := SetProvisionFailedCondition(cd, testcd.FakeInstallLog("InsufficientInstanceCapacity: We currently do not have sufficient capacity"))
+
+	assert.Equal(t, ReasonAWSInsufficientCapacity, cond.Reason)
+
+	var provisionFailedConditions int
+	for _, c := range cd.Status.Conditions {
+		if c.Type == hivev1.ProvisionFailedCondition {
+			provisionFailedConditions++
+			assert.Equal(t, ReasonAWSInsufficientCapacity, c.Reason)
+		}
+	}
+	assert.Equal(t, 1, provisionFailedConditions)
+}
@@ -0,0 +1,175 @@
+package clustersync
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	hiveintclient "github.com/openshift/hive/pkg/client/clientset/versioned"
+)
+
+const (
+	// calculatorInterval is how often the ClusterSync metrics are recalculated.
+	calculatorInterval = 2 * time.Minute
+
+	resultSuccess = "Success"
+)
+
+var (
+	metricSyncSetsApplied = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_syncsets_applied",
+		Help: "Number of SyncSets successfully applied to a cluster.",
+	}, []string{"cluster_deployment", "namespace"})
+
+	metricSyncSetsFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_syncsets_failed",
+		Help: "Number of SyncSets that failed to apply to a cluster.",
+	}, []string{"cluster_deployment", "namespace"})
+
+	metricSelectorSyncSetsApplied = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_selectorsyncsets_applied",
+		Help: "Number of SelectorSyncSets successfully applied to a cluster.",
+	}, []string{"cluster_deployment", "namespace"})
+
+	metricSelectorSyncSetsFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_selectorsyncsets_failed",
+		Help: "Number of SelectorSyncSets that failed to apply to a cluster.",
+	}, []string{"cluster_deployment", "namespace"})
+
+	metricSecondsSinceLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_seconds_since_last_success",
+		Help: "Seconds since the most recent successful sync of any SyncSet or SelectorSyncSet to a cluster.",
+	}, []string{"cluster_deployment", "namespace"})
+
+	metricFailingSyncSets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clustersync_failing_syncsets",
+		Help: "Set to 1 for each SyncSet or SelectorSyncSet currently failing to apply to a cluster.",
+	}, []string{"cluster_deployment", "name", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricSyncSetsApplied)
+	metrics.Registry.MustRegister(metricSyncSetsFailed)
+	metrics.Registry.MustRegister(metricSelectorSyncSetsApplied)
+	metrics.Registry.MustRegister(metricSelectorSyncSetsFailed)
+	metrics.Registry.MustRegister(metricSecondsSinceLastSuccess)
+	metrics.Registry.MustRegister(metricFailingSyncSets)
+}
+
+// Calculator periodically lists ClusterSync objects and publishes gauges summarizing
+// their SyncSet and SelectorSyncSet status, so operators can alert on clusters with
+// stuck or failing syncsets.
+type Calculator struct {
+	Client hiveintclient.Interface
+
+	// Interval is the length of time between metrics calculations. Defaults to
+	// calculatorInterval when zero.
+	Interval time.Duration
+}
+
+// AddToManager registers the Calculator with the manager so that it is started and
+// stopped along with the rest of the controllers.
+func AddToManager(mgr manager.Manager, client hiveintclient.Interface) error {
+	return mgr.Add(&Calculator{Client: client})
+}
+
+// Start begins the periodic calculation of ClusterSync metrics. It satisfies
+// manager.Runnable.
+func (c *Calculator) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval == 0 {
+		interval = calculatorInterval
+	}
+	logger := log.WithField("controller", "clustersyncMetrics")
+	logger.Info("started clustersync metrics calculator goroutine")
+
+	wait.Until(func() { c.calculate(logger) }, interval, ctx.Done())
+	return nil
+}
+
+func (c *Calculator) calculate(logger log.FieldLogger) {
+	defer utilruntime.HandleCrash()
+
+	clusterSyncs, err := c.Client.HiveinternalV1alpha1().ClusterSyncs(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		logger.WithError(err).Error("unable to list ClusterSyncs")
+		return
+	}
+
+	metricSyncSetsApplied.Reset()
+	metricSyncSetsFailed.Reset()
+	metricSelectorSyncSetsApplied.Reset()
+	metricSelectorSyncSetsFailed.Reset()
+	metricSecondsSinceLastSuccess.Reset()
+	metricFailingSyncSets.Reset()
+
+	for _, cs := range clusterSyncs.Items {
+		c.calculateForClusterSync(&cs)
+	}
+
+	logger.WithField("total", len(clusterSyncs.Items)).Debug("calculated clustersync metrics")
+}
+
+func (c *Calculator) calculateForClusterSync(cs *hiveintv1alpha1.ClusterSync) {
+	labels := prometheus.Labels{"cluster_deployment": cs.Name, "namespace": cs.Namespace}
+
+	syncSetsApplied, syncSetsFailed, lastSuccess := summarize(cs.Name, "SyncSet", cs.Status.SyncSets)
+	selectorSyncSetsApplied, selectorSyncSetsFailed, selectorLastSuccess := summarize(cs.Name, "SelectorSyncSet", cs.Status.SelectorSyncSets)
+
+	metricSyncSetsApplied.With(labels).Set(float64(syncSetsApplied))
+	metricSyncSetsFailed.With(labels).Set(float64(syncSetsFailed))
+	metricSelectorSyncSetsApplied.With(labels).Set(float64(selectorSyncSetsApplied))
+	metricSelectorSyncSetsFailed.With(labels).Set(float64(selectorSyncSetsFailed))
+
+	latest := latestTime(lastSuccess, selectorLastSuccess)
+	if latest != nil {
+		metricSecondsSinceLastSuccess.With(labels).Set(time.Since(*latest).Seconds())
+	}
+}
+
+// summarize counts the applied and failed entries of a SyncStatus slice, records a
+// hive_clustersync_failing_syncsets gauge entry for each failing entry, and returns the
+// most recent LastTransitionTime among the currently-Success entries. FirstSuccessTime is
+// deliberately not used here: it is set once the first time a SyncSet ever succeeds and
+// never updated again, so it tracks "first success", not "last success".
+func summarize(clusterDeploymentName, kind string, statuses []hiveintv1alpha1.SyncStatus) (applied, failed int, lastSuccess *time.Time) {
+	for _, status := range statuses {
+		if string(status.Result) == resultSuccess {
+			applied++
+			t := status.LastTransitionTime.Time
+			if lastSuccess == nil || t.After(*lastSuccess) {
+				lastSuccess = &t
+			}
+		} else {
+			failed++
+			metricFailingSyncSets.With(prometheus.Labels{
+				"cluster_deployment": clusterDeploymentName,
+				"name":               status.Name,
+				"kind":               kind,
+			}).Set(1)
+		}
+	}
+	return
+}
+
+func latestTime(times ...*time.Time) *time.Time {
+	var latest *time.Time
+	for _, t := range times {
+		if t == nil {
+			continue
+		}
+		if latest == nil || t.After(*latest) {
+			latest = t
+		}
+	}
+	return latest
+}
@@ -0,0 +1,119 @@
+package clustersync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	hiveintfake "github.com/openshift/hive/pkg/client/clientset/versioned/fake"
+	testcs "github.com/openshift/hive/pkg/test/clustersync"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testCDName    = "test-cluster"
+)
+
+func TestCalculateForClusterSync(t *testing.T) {
+	tenMinutesAgo := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+	tests := []struct {
+		name string
+		cs   *hiveintv1alpha1.ClusterSync
+
+		expectSyncSetsApplied, expectSyncSetsFailed                 float64
+		expectSelectorSyncSetsApplied, expectSelectorSyncSetsFailed float64
+		expectFailingSyncSets                                       int
+	}{
+		{
+			name: "all successful",
+			cs: testcs.BasicBuilder().Build(
+				testcs.WithClusterDeploymentRef(testNamespace, testCDName),
+				testcs.WithSyncSetStatus("ss1", hiveintv1alpha1.SuccessSyncSetResult, ""),
+				testcs.WithSelectorSyncSetStatus("sss1", hiveintv1alpha1.SuccessSyncSetResult, ""),
+				testcs.WithLastTransitionTime("ss1", tenMinutesAgo),
+				testcs.WithLastTransitionTime("sss1", tenMinutesAgo),
+			),
+			expectSyncSetsApplied:         1,
+			expectSelectorSyncSetsApplied: 1,
+		},
+		{
+			name: "mixed results",
+			cs: testcs.BasicBuilder().Build(
+				testcs.WithClusterDeploymentRef(testNamespace, testCDName),
+				testcs.WithSyncSetStatus("ss1", hiveintv1alpha1.SuccessSyncSetResult, ""),
+				testcs.WithLastTransitionTime("ss1", tenMinutesAgo),
+				testcs.WithSyncSetStatus("ss2", hiveintv1alpha1.FailureSyncSetResult, "boom"),
+				testcs.WithSelectorSyncSetStatus("sss1", hiveintv1alpha1.FailureSyncSetResult, "boom"),
+			),
+			expectSyncSetsApplied:        1,
+			expectSyncSetsFailed:         1,
+			expectSelectorSyncSetsFailed: 1,
+			expectFailingSyncSets:        2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metricSyncSetsApplied.Reset()
+			metricSyncSetsFailed.Reset()
+			metricSelectorSyncSetsApplied.Reset()
+			metricSelectorSyncSetsFailed.Reset()
+			metricFailingSyncSets.Reset()
+
+			c := &Calculator{Client: hiveintfake.NewSimpleClientset(test.cs)}
+			c.calculateForClusterSync(test.cs)
+
+			labels := map[string]string{"cluster_deployment": testCDName, "namespace": testNamespace}
+			assert.Equal(t, test.expectSyncSetsApplied, testutil.ToFloat64(metricSyncSetsApplied.With(labels)))
+			assert.Equal(t, test.expectSyncSetsFailed, testutil.ToFloat64(metricSyncSetsFailed.With(labels)))
+			assert.Equal(t, test.expectSelectorSyncSetsApplied, testutil.ToFloat64(metricSelectorSyncSetsApplied.With(labels)))
+			assert.Equal(t, test.expectSelectorSyncSetsFailed, testutil.ToFloat64(metricSelectorSyncSetsFailed.With(labels)))
+			assert.Equal(t, test.expectFailingSyncSets, testutil.CollectAndCount(metricFailingSyncSets))
+		})
+	}
+}
+
+// TestCalculateForClusterSync_SecondsSinceLastSuccess verifies that a SyncSet that has
+// applied successfully on every reconcile for months (a long-ago FirstSuccessTime but a
+// recent LastTransitionTime) is reported as healthy, not stale.
+func TestCalculateForClusterSync_SecondsSinceLastSuccess(t *testing.T) {
+	metricSecondsSinceLastSuccess.Reset()
+
+	monthsAgo := metav1.NewTime(time.Now().Add(-90 * 24 * time.Hour))
+	aMinuteAgo := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	cs := testcs.BasicBuilder().Build(
+		testcs.WithClusterDeploymentRef(testNamespace, testCDName),
+		testcs.WithSyncSetStatus("ss1", hiveintv1alpha1.SuccessSyncSetResult, ""),
+		testcs.WithFirstSuccessTime("ss1", monthsAgo),
+		testcs.WithLastTransitionTime("ss1", aMinuteAgo),
+	)
+
+	c := &Calculator{Client: hiveintfake.NewSimpleClientset(cs)}
+	c.calculateForClusterSync(cs)
+
+	labels := map[string]string{"cluster_deployment": testCDName, "namespace": testNamespace}
+	seconds := testutil.ToFloat64(metricSecondsSinceLastSuccess.With(labels))
+	assert.Less(t, seconds, float64(5*time.Minute/time.Second), "a continuously-healthy SyncSet should not look stale")
+}
+
+func TestCalculate(t *testing.T) {
+	clusterSync := testcs.BasicBuilder().Build(
+		testcs.WithClusterDeploymentRef(testNamespace, testCDName),
+		testcs.WithSyncSetStatus("ss1", hiveintv1alpha1.FailureSyncSetResult, "boom"),
+	)
+
+	client := hiveintfake.NewSimpleClientset(clusterSync)
+	c := &Calculator{Client: client}
+	c.calculate(log.StandardLogger())
+
+	labels := map[string]string{"cluster_deployment": testCDName, "namespace": testNamespace}
+	assert.Equal(t, float64(1), testutil.ToFloat64(metricSyncSetsFailed.With(labels)))
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	hiveintclient "github.com/openshift/hive/pkg/client/clientset/versioned"
+	"github.com/openshift/hive/pkg/controller/metrics/clustersync"
+)
+
+// AddToManager registers all of the metrics collectors under pkg/controller/metrics with
+// the manager, so each collector starts and stops with the manager.
+//
+// TODO: cmd/manager does not exist in this slice of the repo and so does not yet call
+// this function. Until it does, none of these collectors actually run outside of their
+// own tests; wire this in alongside the rest of the controller startup when that code
+// lands.
+func AddToManager(mgr manager.Manager, hiveintClient hiveintclient.Interface) error {
+	return clustersync.AddToManager(mgr, hiveintClient)
+}
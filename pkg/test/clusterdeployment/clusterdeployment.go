@@ -129,6 +129,24 @@ func Broken() Option {
 	})
 }
 
+// WithProvisionFailedReason sets ProvisionFailedCondition=True with the given classified
+// reason and message, as produced by the clusterdeployment controller's provision failure
+// classifier.
+func WithProvisionFailedReason(reason, message string) Option {
+	return WithCondition(hivev1.ClusterDeploymentCondition{
+		Type:    hivev1.ProvisionFailedCondition,
+		Status:  v1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// FakeInstallLog is a helper, not an Option, for building the raw install log bytes that
+// the provision failure classifiers in the clusterdeployment controller consume in tests.
+func FakeInstallLog(text string) []byte {
+	return []byte(text)
+}
+
 func WithUnclaimedClusterPoolReference(namespace, poolName string) Option {
 	return WithClusterPoolReference(namespace, poolName, "")
 }
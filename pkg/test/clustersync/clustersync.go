@@ -0,0 +1,157 @@
+package clustersync
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/hive/pkg/test/generic"
+)
+
+// Option defines a function signature for any function that wants to be passed into Build
+type Option func(*hiveintv1alpha1.ClusterSync)
+
+// Build runs each of the functions passed in to generate the object.
+func Build(opts ...Option) *hiveintv1alpha1.ClusterSync {
+	retval := &hiveintv1alpha1.ClusterSync{}
+	for _, o := range opts {
+		o(retval)
+	}
+
+	return retval
+}
+
+type Builder interface {
+	Build(opts ...Option) *hiveintv1alpha1.ClusterSync
+
+	Options(opts ...Option) Builder
+
+	GenericOptions(opts ...generic.Option) Builder
+}
+
+func BasicBuilder() Builder {
+	return &builder{}
+}
+
+func FullBuilder(namespace, name string, typer runtime.ObjectTyper) Builder {
+	b := &builder{}
+	return b.GenericOptions(
+		generic.WithTypeMeta(typer),
+		generic.WithResourceVersion("1"),
+		generic.WithNamespace(namespace),
+		generic.WithName(name),
+	)
+}
+
+type builder struct {
+	options []Option
+}
+
+func (b *builder) Build(opts ...Option) *hiveintv1alpha1.ClusterSync {
+	return Build(append(b.options, opts...)...)
+}
+
+func (b *builder) Options(opts ...Option) Builder {
+	return &builder{
+		options: append(b.options, opts...),
+	}
+}
+
+func (b *builder) GenericOptions(opts ...generic.Option) Builder {
+	options := make([]Option, len(opts))
+	for i, o := range opts {
+		options[i] = Generic(o)
+	}
+	return b.Options(options...)
+}
+
+// Generic allows common functions applicable to all objects to be used as Options to Build
+func Generic(opt generic.Option) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		opt(clusterSync)
+	}
+}
+
+// WithName sets the object.Name field when building an object with Build.
+func WithName(name string) Option {
+	return Generic(generic.WithName(name))
+}
+
+// WithNamespace sets the object.Namespace field when building an object with Build.
+func WithNamespace(namespace string) Option {
+	return Generic(generic.WithNamespace(namespace))
+}
+
+// WithClusterDeploymentRef sets the owning ClusterDeployment's namespace and name. The
+// ClusterSync shares its own namespace/name with the ClusterDeployment it belongs to, so
+// this also sets those fields.
+func WithClusterDeploymentRef(namespace, name string) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		clusterSync.Namespace = namespace
+		clusterSync.Name = name
+	}
+}
+
+// WithSyncSetStatus adds or replaces the named SyncSet entry in Status.SyncSets.
+func WithSyncSetStatus(name string, result hiveintv1alpha1.SyncSetResult, message string) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		clusterSync.Status.SyncSets = setSyncStatus(clusterSync.Status.SyncSets, name, result, message)
+	}
+}
+
+// WithSelectorSyncSetStatus adds or replaces the named SelectorSyncSet entry in
+// Status.SelectorSyncSets.
+func WithSelectorSyncSetStatus(name string, result hiveintv1alpha1.SyncSetResult, message string) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		clusterSync.Status.SelectorSyncSets = setSyncStatus(clusterSync.Status.SelectorSyncSets, name, result, message)
+	}
+}
+
+// WithFirstSuccessTime sets FirstSuccessTime on the named SyncSet or SelectorSyncSet
+// status entry, whichever already exists with that name.
+func WithFirstSuccessTime(name string, t metav1.Time) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		for i, s := range clusterSync.Status.SyncSets {
+			if s.Name == name {
+				clusterSync.Status.SyncSets[i].FirstSuccessTime = &t
+			}
+		}
+		for i, s := range clusterSync.Status.SelectorSyncSets {
+			if s.Name == name {
+				clusterSync.Status.SelectorSyncSets[i].FirstSuccessTime = &t
+			}
+		}
+	}
+}
+
+// WithLastTransitionTime sets LastTransitionTime on the named SyncSet or SelectorSyncSet
+// status entry, whichever already exists with that name.
+func WithLastTransitionTime(name string, t metav1.Time) Option {
+	return func(clusterSync *hiveintv1alpha1.ClusterSync) {
+		for i, s := range clusterSync.Status.SyncSets {
+			if s.Name == name {
+				clusterSync.Status.SyncSets[i].LastTransitionTime = t
+			}
+		}
+		for i, s := range clusterSync.Status.SelectorSyncSets {
+			if s.Name == name {
+				clusterSync.Status.SelectorSyncSets[i].LastTransitionTime = t
+			}
+		}
+	}
+}
+
+func setSyncStatus(statuses []hiveintv1alpha1.SyncStatus, name string, result hiveintv1alpha1.SyncSetResult, message string) []hiveintv1alpha1.SyncStatus {
+	for i, s := range statuses {
+		if s.Name == name {
+			statuses[i].Result = result
+			statuses[i].FailureMessage = message
+			return statuses
+		}
+	}
+	return append(statuses, hiveintv1alpha1.SyncStatus{
+		Name:           name,
+		Result:         result,
+		FailureMessage: message,
+	})
+}